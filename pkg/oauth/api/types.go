@@ -0,0 +1,80 @@
+package api
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+)
+
+// AccessToken is a bearer token that a client may use to authenticate to the API
+// on behalf of a user.
+type AccessToken struct {
+	api.TypeMeta
+	api.ObjectMeta
+
+	// ClientName references the Client that created this token.
+	ClientName string
+
+	// UserName is the user name associated with this token.
+	UserName string
+
+	// UserUID is the unique UID associated with this token. UserUID and UserName
+	// must both match for this token to be valid.
+	UserUID string
+
+	// AuthorizeToken contains the token that authorized this token.
+	AuthorizeToken string
+
+	// RefreshToken is the value by which this token can be renewed. Can be blank.
+	RefreshToken string
+
+	// ExpiresIn is the seconds from CreationTimestamp this token is valid for.
+	ExpiresIn int64
+
+	// Expiry is the time this token stops being valid. It is stamped at create
+	// time as CreationTimestamp + ExpiresIn and is persisted so that it survives
+	// clock skew between create and lookup.
+	Expiry util.Time
+
+	// Status is set on retrieval to reflect whether the token is still usable.
+	// It is never persisted.
+	Status string
+}
+
+// AccessTokenList is a collection of AccessTokens.
+type AccessTokenList struct {
+	api.TypeMeta
+	Items []AccessToken
+}
+
+// StatusActive and StatusExpired are the values AccessToken.Status may hold.
+const (
+	StatusActive  = "Active"
+	StatusExpired = "Expired"
+)
+
+// RefreshToken is a long-lived token that a client may exchange for a new
+// AccessToken once the original AccessToken has expired.
+type RefreshToken struct {
+	api.TypeMeta
+	api.ObjectMeta
+
+	// ClientName references the Client the originating AccessToken was issued to.
+	ClientName string
+
+	// UserName is the user name associated with this token.
+	UserName string
+
+	// UserUID is the unique UID associated with this token.
+	UserUID string
+
+	// AccessTokenName is the name of the AccessToken this refresh token was
+	// issued alongside. When the refresh token is redeemed, that AccessToken is
+	// superseded by a newly issued one.
+	AccessTokenName string
+}
+
+// RefreshTokenList is a collection of RefreshTokens.
+type RefreshTokenList struct {
+	api.TypeMeta
+	Items []RefreshToken
+}
@@ -0,0 +1,43 @@
+package validation
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/fielderrors"
+
+	oapi "github.com/openshift/origin/pkg/oauth/api"
+)
+
+// ValidateAccessToken tests required fields for an AccessToken.
+func ValidateAccessToken(token *oapi.AccessToken) fielderrors.ValidationErrorList {
+	result := fielderrors.ValidationErrorList{}
+	if len(token.Name) == 0 {
+		result = append(result, fielderrors.NewFieldRequired("name"))
+	}
+	if len(token.ClientName) == 0 {
+		result = append(result, fielderrors.NewFieldRequired("clientName"))
+	}
+	if len(token.UserName) == 0 {
+		result = append(result, fielderrors.NewFieldRequired("userName"))
+	}
+	if len(token.UserUID) == 0 {
+		result = append(result, fielderrors.NewFieldRequired("userUID"))
+	}
+	return result
+}
+
+// ValidateRefreshToken tests required fields for a RefreshToken.
+func ValidateRefreshToken(token *oapi.RefreshToken) fielderrors.ValidationErrorList {
+	result := fielderrors.ValidationErrorList{}
+	if len(token.Name) == 0 {
+		result = append(result, fielderrors.NewFieldRequired("name"))
+	}
+	if len(token.ClientName) == 0 {
+		result = append(result, fielderrors.NewFieldRequired("clientName"))
+	}
+	if len(token.UserName) == 0 {
+		result = append(result, fielderrors.NewFieldRequired("userName"))
+	}
+	if len(token.AccessTokenName) == 0 {
+		result = append(result, fielderrors.NewFieldRequired("accessTokenName"))
+	}
+	return result
+}
@@ -0,0 +1,150 @@
+package introspection
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+
+	oapi "github.com/openshift/origin/pkg/oauth/api"
+	"github.com/openshift/origin/pkg/oauth/registry/accesstoken"
+)
+
+// ClientAuthenticator authenticates the OAuth client calling the introspection
+// or revocation endpoint and returns the ClientName recorded on its tokens.
+type ClientAuthenticator interface {
+	AuthenticateClient(id, secret string) (name string, ok bool, err error)
+}
+
+// Handler serves RFC 7662 token introspection and RFC 7009 token revocation on
+// top of an existing accesstoken.Registry.
+type Handler struct {
+	tokens  accesstoken.Registry
+	clients ClientAuthenticator
+	hasher  accesstoken.TokenHasher
+}
+
+// NewHandler returns a Handler that authenticates callers against clients and
+// answers introspection/revocation requests from tokens. Presented token
+// values are hashed with hasher before being looked up, since the registry
+// stores tokens by their hash rather than their plaintext value.
+func NewHandler(tokens accesstoken.Registry, clients ClientAuthenticator, hasher accesstoken.TokenHasher) *Handler {
+	return &Handler{tokens: tokens, clients: clients, hasher: hasher}
+}
+
+func (h *Handler) hasherOrDefault() accesstoken.TokenHasher {
+	if h.hasher != nil {
+		return h.hasher
+	}
+	return accesstoken.SHA256Hasher{}
+}
+
+// introspectResponse is the RFC 7662 introspection response body. Scope is
+// omitted: oapi.AccessToken carries no scope data to populate it with, and
+// RFC 7662 makes it OPTIONAL.
+type introspectResponse struct {
+	Active   bool   `json:"active"`
+	ClientID string `json:"client_id,omitempty"`
+	Username string `json:"username,omitempty"`
+	Exp      int64  `json:"exp,omitempty"`
+}
+
+// Introspect implements POST /oauth/introspect.
+func (h *Handler) Introspect(w http.ResponseWriter, req *http.Request) {
+	callerName, ok := h.authenticate(req)
+	if !ok {
+		http.Error(w, "invalid client", http.StatusUnauthorized)
+		return
+	}
+	if err := req.ParseForm(); err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	token, active := h.lookupActiveToken(req.PostForm.Get("token"), callerName)
+	if !active {
+		writeJSON(w, introspectResponse{Active: false})
+		return
+	}
+
+	response := introspectResponse{
+		Active:   true,
+		ClientID: token.ClientName,
+		Username: token.UserName,
+	}
+	if token.ExpiresIn > 0 {
+		response.Exp = token.Expiry.Time.Unix()
+	}
+	writeJSON(w, response)
+}
+
+// Revoke implements POST /oauth/revoke.
+func (h *Handler) Revoke(w http.ResponseWriter, req *http.Request) {
+	callerName, ok := h.authenticate(req)
+	if !ok {
+		http.Error(w, "invalid client", http.StatusUnauthorized)
+		return
+	}
+	if err := req.ParseForm(); err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	ctx := api.NewContext()
+	token, active := h.lookupActiveToken(req.PostForm.Get("token"), callerName)
+	if !active {
+		// RFC 7009: revoking an already-invalid token is not an error.
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if err := h.tokens.DeleteAccessToken(ctx, token.Name, "revoked via /oauth/revoke"); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// lookupActiveToken resolves tokenValue and reports whether it is usable by
+// callerName, without distinguishing unknown, expired, or wrong-client tokens
+// to the caller. Like accesstoken.REST.Get, it falls back to a legacy
+// plaintext lookup for tokens created before hashing was enabled; remove that
+// fallback once the migration window closes.
+func (h *Handler) lookupActiveToken(tokenValue, callerName string) (*oapi.AccessToken, bool) {
+	ctx := api.NewContext()
+	hashed, err := h.hasherOrDefault().Hash(tokenValue)
+	if err != nil {
+		return nil, false
+	}
+	token, err := h.tokens.GetAccessToken(ctx, hashed)
+	if err != nil || token == nil {
+		token, err = h.tokens.GetAccessToken(ctx, tokenValue)
+		if err != nil || token == nil {
+			return nil, false
+		}
+	}
+	if token.ExpiresIn > 0 && token.Expiry.Time.Before(time.Now()) {
+		return nil, false
+	}
+	if token.ClientName != callerName {
+		return nil, false
+	}
+	return token, true
+}
+
+func (h *Handler) authenticate(req *http.Request) (string, bool) {
+	id, secret, ok := req.BasicAuth()
+	if !ok {
+		return "", false
+	}
+	name, ok, err := h.clients.AuthenticateClient(id, secret)
+	if err != nil || !ok {
+		return "", false
+	}
+	return name, true
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
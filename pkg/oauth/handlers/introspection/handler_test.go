@@ -0,0 +1,194 @@
+package introspection
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
+	oapi "github.com/openshift/origin/pkg/oauth/api"
+	"github.com/openshift/origin/pkg/oauth/registry/accesstoken"
+	"github.com/openshift/origin/pkg/oauth/registry/test"
+)
+
+type fakeClientAuthenticator struct {
+	name string
+	ok   bool
+}
+
+func (f fakeClientAuthenticator) AuthenticateClient(id, secret string) (string, bool, error) {
+	return f.name, f.ok, nil
+}
+
+func introspectRequest(t *testing.T, token string) *http.Request {
+	req, err := http.NewRequest("POST", "/oauth/introspect", strings.NewReader(url.Values{"token": {token}}.Encode()))
+	if err != nil {
+		t.Fatalf("unexpected error building request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("clientID", "clientSecret")
+	return req
+}
+
+func TestIntrospect(t *testing.T) {
+	tests := []struct {
+		name       string
+		registry   test.AccessTokenRegistry
+		clientOK   bool
+		wantActive bool
+	}{
+		{
+			name:       "unknown token",
+			registry:   test.AccessTokenRegistry{Err: errors.New("not found")},
+			clientOK:   true,
+			wantActive: false,
+		},
+		{
+			name: "valid token",
+			registry: test.AccessTokenRegistry{
+				AccessToken: &oapi.AccessToken{
+					ObjectMeta: api.ObjectMeta{Name: "accessTokenName"},
+					ClientName: "clientName",
+					UserName:   "userName",
+					ExpiresIn:  86400,
+					Expiry:     util.NewTime(time.Now().Add(time.Hour)),
+				},
+			},
+			clientOK:   true,
+			wantActive: true,
+		},
+		{
+			name: "expired token",
+			registry: test.AccessTokenRegistry{
+				AccessToken: &oapi.AccessToken{
+					ObjectMeta: api.ObjectMeta{Name: "accessTokenName"},
+					ClientName: "clientName",
+					ExpiresIn:  86400,
+					Expiry:     util.NewTime(time.Now().Add(-time.Hour)),
+				},
+			},
+			clientOK:   true,
+			wantActive: false,
+		},
+		{
+			name: "wrong client",
+			registry: test.AccessTokenRegistry{
+				AccessToken: &oapi.AccessToken{
+					ObjectMeta: api.ObjectMeta{Name: "accessTokenName"},
+					ClientName: "someOtherClient",
+					ExpiresIn:  86400,
+					Expiry:     util.NewTime(time.Now().Add(time.Hour)),
+				},
+			},
+			clientOK:   true,
+			wantActive: false,
+		},
+	}
+
+	for _, tt := range tests {
+		handler := NewHandler(&tt.registry, fakeClientAuthenticator{name: "clientName", ok: tt.clientOK}, accesstoken.SHA256Hasher{})
+
+		rr := httptest.NewRecorder()
+		handler.Introspect(rr, introspectRequest(t, "someTokenValue"))
+
+		gotActive := strings.Contains(rr.Body.String(), `"active":true`)
+		if gotActive != tt.wantActive {
+			t.Errorf("%s: expected active=%v, got body %q", tt.name, tt.wantActive, rr.Body.String())
+		}
+	}
+}
+
+func TestIntrospectNonExpiringTokenOmitsExp(t *testing.T) {
+	registry := test.AccessTokenRegistry{
+		AccessToken: &oapi.AccessToken{
+			ObjectMeta: api.ObjectMeta{Name: "accessTokenName"},
+			ClientName: "clientName",
+			UserName:   "userName",
+		},
+	}
+	handler := NewHandler(&registry, fakeClientAuthenticator{name: "clientName", ok: true}, accesstoken.SHA256Hasher{})
+
+	rr := httptest.NewRecorder()
+	handler.Introspect(rr, introspectRequest(t, "someTokenValue"))
+
+	if strings.Contains(rr.Body.String(), `"exp"`) {
+		t.Errorf("expected a non-expiring token to omit exp, got %q", rr.Body.String())
+	}
+}
+
+func TestIntrospectUnauthenticatedClient(t *testing.T) {
+	registry := test.AccessTokenRegistry{}
+	handler := NewHandler(&registry, fakeClientAuthenticator{ok: false}, accesstoken.SHA256Hasher{})
+
+	rr := httptest.NewRecorder()
+	handler.Introspect(rr, introspectRequest(t, "someTokenValue"))
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rr.Code)
+	}
+}
+
+// keyedAccessTokenRegistry is a tiny in-memory registry, distinct from
+// test.AccessTokenRegistry, that actually distinguishes lookups by name so the
+// legacy-plaintext migration fallback can be exercised.
+type keyedAccessTokenRegistry struct {
+	test.AccessTokenRegistry
+	tokensByName map[string]*oapi.AccessToken
+}
+
+func (r *keyedAccessTokenRegistry) GetAccessToken(ctx api.Context, name string) (*oapi.AccessToken, error) {
+	if token, ok := r.tokensByName[name]; ok {
+		return token, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func TestIntrospectLegacyPlaintextFallback(t *testing.T) {
+	registry := &keyedAccessTokenRegistry{
+		tokensByName: map[string]*oapi.AccessToken{
+			"legacyPlaintextValue": {
+				ObjectMeta: api.ObjectMeta{Name: "legacyPlaintextValue"},
+				ClientName: "clientName",
+				UserName:   "userName",
+				ExpiresIn:  86400,
+				Expiry:     util.NewTime(time.Now().Add(time.Hour)),
+			},
+		},
+	}
+	handler := NewHandler(registry, fakeClientAuthenticator{name: "clientName", ok: true}, accesstoken.SHA256Hasher{})
+
+	rr := httptest.NewRecorder()
+	handler.Introspect(rr, introspectRequest(t, "legacyPlaintextValue"))
+
+	if !strings.Contains(rr.Body.String(), `"active":true`) {
+		t.Errorf("expected a pre-hashing token to still introspect as active, got %q", rr.Body.String())
+	}
+}
+
+func TestRevokeValid(t *testing.T) {
+	registry := test.AccessTokenRegistry{
+		AccessToken: &oapi.AccessToken{
+			ObjectMeta: api.ObjectMeta{Name: "accessTokenName"},
+			ClientName: "clientName",
+			ExpiresIn:  86400,
+			Expiry:     util.NewTime(time.Now().Add(time.Hour)),
+		},
+	}
+	handler := NewHandler(&registry, fakeClientAuthenticator{name: "clientName", ok: true}, accesstoken.SHA256Hasher{})
+
+	rr := httptest.NewRecorder()
+	handler.Revoke(rr, introspectRequest(t, "accessTokenName"))
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d", rr.Code)
+	}
+	if registry.DeletedAccessTokenName != "accessTokenName" {
+		t.Errorf("expected token to be revoked, got %q", registry.DeletedAccessTokenName)
+	}
+}
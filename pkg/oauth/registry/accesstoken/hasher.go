@@ -0,0 +1,61 @@
+package accesstoken
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// TokenHasher computes the form of a bearer token that is persisted in place of
+// the plaintext.
+//
+// The registry indexes access tokens by their hashed name, so Hash must be
+// deterministic: hashing the same token twice must produce the same result.
+// That rules out salted schemes like bcrypt as the hasher REST looks tokens up
+// with; SHA256Hasher is used unless a REST is explicitly configured otherwise.
+type TokenHasher interface {
+	// Hash returns the stored form of token.
+	Hash(token string) (string, error)
+}
+
+// SHA256Hasher hashes tokens with SHA-256 over the token and a server-side
+// pepper. It is deterministic, so the result can be used directly as the
+// registry's lookup key.
+type SHA256Hasher struct {
+	// Pepper is mixed into every hash. It is a deployment-wide secret, not a
+	// per-token salt, so it does not need to be stored alongside the hash.
+	Pepper string
+}
+
+func (h SHA256Hasher) Hash(token string) (string, error) {
+	sum := sha256.Sum256([]byte(h.Pepper + token))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// BcryptHasher hashes tokens with bcrypt. Because bcrypt salts every hash
+// differently, the same token hashes to a different value each time, so it
+// cannot serve as this registry's lookup key. It is provided for callers that
+// verify a token against an already-loaded AccessToken.Name rather than
+// through REST.Get, e.g. out-of-band audit tooling.
+type BcryptHasher struct {
+	// Cost is the bcrypt work factor. Zero means bcrypt.DefaultCost.
+	Cost int
+}
+
+func (h BcryptHasher) Hash(token string) (string, error) {
+	cost := h.Cost
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(token), cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+// Matches reports whether token is the plaintext that hashed produced.
+func (h BcryptHasher) Matches(token, hashed string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hashed), []byte(token)) == nil
+}
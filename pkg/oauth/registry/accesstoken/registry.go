@@ -0,0 +1,24 @@
+package accesstoken
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+
+	oapi "github.com/openshift/origin/pkg/oauth/api"
+)
+
+// Registry is an interface implemented by things that know how to store AccessToken objects.
+type Registry interface {
+	// ListAccessTokens obtains a list of access tokens having labels and fields which match selector.
+	ListAccessTokens(ctx api.Context, label, field labels.Selector) (*oapi.AccessTokenList, error)
+	// GetAccessToken retrieves a specific access token.
+	GetAccessToken(ctx api.Context, name string) (*oapi.AccessToken, error)
+	// CreateAccessToken creates a new access token. The token is stored as given;
+	// its Expiry is stamped from ExpiresIn by REST before this is called.
+	CreateAccessToken(ctx api.Context, token *oapi.AccessToken) error
+	// DeleteAccessToken deletes an access token, recording reason for audit purposes.
+	DeleteAccessToken(ctx api.Context, name, reason string) error
+	// DeleteAccessTokens deletes every access token matching label and field,
+	// recording reason for audit purposes, and returns the tokens that were deleted.
+	DeleteAccessTokens(ctx api.Context, label, field labels.Selector, reason string) (*oapi.AccessTokenList, error)
+}
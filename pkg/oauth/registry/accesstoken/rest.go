@@ -0,0 +1,291 @@
+package accesstoken
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
+	oapi "github.com/openshift/origin/pkg/oauth/api"
+	"github.com/openshift/origin/pkg/oauth/api/validation"
+	"github.com/openshift/origin/pkg/oauth/registry/refreshtoken"
+)
+
+// defaultAccessTokenExpiresInSeconds is used when an incoming AccessToken does not
+// specify its own ExpiresIn.
+const defaultAccessTokenExpiresInSeconds = 86400
+
+// defaultHasher is used by a REST whose hasher was left unset.
+var defaultHasher TokenHasher = SHA256Hasher{}
+
+// REST implements the RESTStorage interface for AccessToken objects.
+type REST struct {
+	registry      Registry
+	refreshTokens refreshtoken.Registry
+	hasher        TokenHasher
+}
+
+// NewREST returns a RESTStorage object that will work against AccessToken objects,
+// issuing and rotating refresh tokens against refreshTokens and hashing bearer
+// token values with hasher before they are persisted.
+func NewREST(registry Registry, refreshTokens refreshtoken.Registry, hasher TokenHasher) *REST {
+	return &REST{registry: registry, refreshTokens: refreshTokens, hasher: hasher}
+}
+
+func (r *REST) hasherOrDefault() TokenHasher {
+	if r.hasher != nil {
+		return r.hasher
+	}
+	return defaultHasher
+}
+
+// New returns a new AccessToken for use with Create and Update.
+func (r *REST) New() runtime.Object {
+	return &oapi.AccessToken{}
+}
+
+// List obtains a list of access tokens that match selector. The Name of each
+// item is the token's hash, not the bearer value, so it is cleared before
+// being returned to clients.
+func (r *REST) List(ctx api.Context, label, field labels.Selector) (runtime.Object, error) {
+	tokens, err := r.registry.ListAccessTokens(ctx, label, field)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tokens.Items {
+		stampStatus(&tokens.Items[i])
+		tokens.Items[i].Name = ""
+	}
+	return tokens, nil
+}
+
+// Get retrieves an access token by its plaintext bearer value, hashing it to
+// perform the lookup. Tokens created before hashing was enabled are found by
+// falling back to a plaintext lookup; remove that fallback once the migration
+// window closes.
+func (r *REST) Get(ctx api.Context, name string) (runtime.Object, error) {
+	hashed, err := r.hasherOrDefault().Hash(name)
+	if err != nil {
+		return nil, err
+	}
+	token, err := r.registry.GetAccessToken(ctx, hashed)
+	if err != nil || token == nil {
+		legacyToken, legacyErr := r.registry.GetAccessToken(ctx, name)
+		if legacyErr != nil {
+			return nil, err
+		}
+		if legacyToken == nil {
+			return nil, errors.NewNotFound("accessToken", name)
+		}
+		token = legacyToken
+	}
+	stampStatus(token)
+	return token, nil
+}
+
+// Delete asynchronously revokes an access token identified by its plaintext
+// bearer value. See Get for the legacy-plaintext migration fallback.
+func (r *REST) Delete(ctx api.Context, name string) (<-chan apiserver.RESTResult, error) {
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		hashed, err := r.hasherOrDefault().Hash(name)
+		if err != nil {
+			return nil, err
+		}
+		if delErr := r.registry.DeleteAccessToken(ctx, hashed, "revoked"); delErr != nil {
+			if legacyErr := r.registry.DeleteAccessToken(ctx, name, "revoked"); legacyErr != nil {
+				return nil, delErr
+			}
+		}
+		return &api.Status{Status: api.StatusSuccess}, nil
+	}), nil
+}
+
+// DeleteCollection asynchronously revokes every access token matching label and
+// field, e.g. to log a user out everywhere with a userName selector.
+func (r *REST) DeleteCollection(ctx api.Context, label, field labels.Selector) (<-chan apiserver.RESTResult, error) {
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		deleted, err := r.registry.DeleteAccessTokens(ctx, label, field, "bulk revocation")
+		if err != nil {
+			return nil, err
+		}
+		return deleted, nil
+	}), nil
+}
+
+// Create asynchronously creates an access token. The submitted Name is the
+// one-time plaintext bearer value: it is hashed before being handed to the
+// registry, and only the async response echoes it back in the clear. If r
+// was constructed with a refreshTokens registry, an initial RefreshToken is
+// issued alongside it and its plaintext value is likewise returned only in
+// this response, on AccessToken.RefreshToken.
+func (r *REST) Create(ctx api.Context, obj runtime.Object) (<-chan apiserver.RESTResult, error) {
+	token, ok := obj.(*oapi.AccessToken)
+	if !ok {
+		return nil, fmt.Errorf("not an access token: %#v", obj)
+	}
+
+	if !api.ValidNamespace(ctx, &token.ObjectMeta) {
+		return nil, errors.NewBadRequest("the namespace of the access token does not match the namespace on the request")
+	}
+
+	if token.ExpiresIn == 0 {
+		token.ExpiresIn = defaultAccessTokenExpiresInSeconds
+	}
+	if errs := validation.ValidateAccessToken(token); len(errs) > 0 {
+		return nil, errors.NewInvalid("accessToken", token.Name, errs)
+	}
+
+	plaintext := token.Name
+	hashed, err := r.hasherOrDefault().Hash(plaintext)
+	if err != nil {
+		return nil, err
+	}
+	stored := *token
+	stored.Name = hashed
+	stored.Status = ""
+	stampExpiry(&stored)
+
+	var refreshToken *oapi.RefreshToken
+	var refreshPlaintext string
+	if r.refreshTokens != nil {
+		refreshPlaintext, err = generateTokenValue()
+		if err != nil {
+			return nil, err
+		}
+		hashedRefresh, err := r.hasherOrDefault().Hash(refreshPlaintext)
+		if err != nil {
+			return nil, err
+		}
+		refreshToken = &oapi.RefreshToken{
+			ObjectMeta:      api.ObjectMeta{Name: hashedRefresh},
+			ClientName:      stored.ClientName,
+			UserName:        stored.UserName,
+			UserUID:         stored.UserUID,
+			AccessTokenName: stored.Name,
+		}
+	}
+
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		if err := r.registry.CreateAccessToken(ctx, &stored); err != nil {
+			return nil, err
+		}
+		if refreshToken != nil {
+			if err := r.refreshTokens.CreateRefreshToken(ctx, refreshToken); err != nil {
+				return nil, err
+			}
+		}
+		response := stored
+		response.Name = plaintext
+		response.RefreshToken = refreshPlaintext
+		return &response, nil
+	}), nil
+}
+
+// Update is not supported for access tokens; they are immutable once issued.
+func (r *REST) Update(ctx api.Context, obj runtime.Object) (<-chan apiserver.RESTResult, error) {
+	return nil, fmt.Errorf("AccessTokens may not be changed.")
+}
+
+// Refresh redeems a refresh token for a newly issued AccessToken, rotating the
+// refresh token in the same operation. The old refresh token is deleted
+// immediately after being read and before any new token is minted, so a
+// second, concurrent redemption of the same refresh value loses the delete
+// and fails rather than also minting a token; this is not a true
+// compare-and-delete (the registry interface has no such primitive), so it
+// depends on the underlying store erroring a delete of an already-deleted
+// key. Any failure after that delete returns only the error: the caller gets
+// neither a new access token nor a new refresh token, and an AccessToken
+// already written to the registry is not removed.
+func (r *REST) Refresh(ctx api.Context, refreshTokenValue string) (*oapi.AccessToken, error) {
+	hashedRefresh, err := r.hasherOrDefault().Hash(refreshTokenValue)
+	if err != nil {
+		return nil, err
+	}
+	oldRefresh, err := r.refreshTokens.GetRefreshToken(ctx, hashedRefresh)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.refreshTokens.DeleteRefreshToken(ctx, oldRefresh.Name); err != nil {
+		return nil, err
+	}
+
+	accessPlaintext, err := generateTokenValue()
+	if err != nil {
+		return nil, err
+	}
+	hashedAccess, err := r.hasherOrDefault().Hash(accessPlaintext)
+	if err != nil {
+		return nil, err
+	}
+	stored := &oapi.AccessToken{
+		ObjectMeta: api.ObjectMeta{Name: hashedAccess},
+		ClientName: oldRefresh.ClientName,
+		UserName:   oldRefresh.UserName,
+		UserUID:    oldRefresh.UserUID,
+		ExpiresIn:  defaultAccessTokenExpiresInSeconds,
+	}
+	stampExpiry(stored)
+	if err := r.registry.CreateAccessToken(ctx, stored); err != nil {
+		return nil, err
+	}
+
+	refreshPlaintext, err := generateTokenValue()
+	if err != nil {
+		return nil, err
+	}
+	hashedNewRefresh, err := r.hasherOrDefault().Hash(refreshPlaintext)
+	if err != nil {
+		return nil, err
+	}
+	newRefresh := &oapi.RefreshToken{
+		ObjectMeta:      api.ObjectMeta{Name: hashedNewRefresh},
+		ClientName:      oldRefresh.ClientName,
+		UserName:        oldRefresh.UserName,
+		UserUID:         oldRefresh.UserUID,
+		AccessTokenName: stored.Name,
+	}
+	if err := r.refreshTokens.CreateRefreshToken(ctx, newRefresh); err != nil {
+		return nil, err
+	}
+
+	response := *stored
+	response.Name = accessPlaintext
+	response.RefreshToken = refreshPlaintext
+	return &response, nil
+}
+
+// generateTokenValue returns a fresh, random bearer token value.
+func generateTokenValue() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// stampExpiry stamps CreationTimestamp and, following the approach taken by dex,
+// derives Expiry as CreationTimestamp + ExpiresIn at the moment of creation.
+func stampExpiry(token *oapi.AccessToken) {
+	now := util.Now()
+	token.CreationTimestamp = now
+	if token.ExpiresIn > 0 {
+		token.Expiry = util.NewTime(now.Add(time.Duration(token.ExpiresIn) * time.Second))
+	}
+}
+
+// stampStatus marks a token as Expired once its Expiry has passed, rather than
+// letting it be handed back to callers looking like a valid, usable token.
+func stampStatus(token *oapi.AccessToken) {
+	if token.ExpiresIn > 0 && token.Expiry.Time.Before(time.Now()) {
+		token.Status = oapi.StatusExpired
+		return
+	}
+	token.Status = oapi.StatusActive
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	oapi "github.com/openshift/origin/pkg/oauth/api"
 	"github.com/openshift/origin/pkg/oauth/registry/test"
 )
@@ -44,7 +45,7 @@ func TestCreateStorageError(t *testing.T) {
 		UserUID:    "userUID",
 	}
 
-	ctx := api.NewContext()
+	ctx := api.WithNamespace(api.NewContext(), "default")
 	channel, err := storage.Create(ctx, accessToken)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
@@ -79,7 +80,7 @@ func TestCreateValid(t *testing.T) {
 		UserUID:    "userUID",
 	}
 
-	ctx := api.NewContext()
+	ctx := api.WithNamespace(api.NewContext(), "default")
 	channel, err := storage.Create(ctx, accessToken)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
@@ -91,13 +92,69 @@ func TestCreateValid(t *testing.T) {
 		case *api.Status:
 			t.Errorf("Got back unexpected status: %#v", r)
 		case *oapi.AccessToken:
-		// expected case
+			if r.Name != "accessTokenName" {
+				t.Errorf("expected the one-time plaintext token name back, got %q", r.Name)
+			}
 		default:
 			t.Errorf("Got unexpected type: %#v", r)
 		}
 	case <-time.After(time.Millisecond * 100):
 		t.Error("Unexpected timeout from async channel")
 	}
+
+	if registry.CreatedAccessToken == nil {
+		t.Fatalf("expected registry to receive the created token")
+	}
+	if registry.CreatedAccessToken.Name == "accessTokenName" {
+		t.Errorf("expected registry to receive a hashed name, got the plaintext back unchanged")
+	}
+}
+
+func TestCreateIssuesRefreshToken(t *testing.T) {
+	accessTokens := test.AccessTokenRegistry{}
+	refreshTokens := test.RefreshTokenRegistry{}
+	storage := REST{
+		registry:      &accessTokens,
+		refreshTokens: &refreshTokens,
+	}
+	accessToken := &oapi.AccessToken{
+		ObjectMeta: api.ObjectMeta{Name: "accessTokenName"},
+		ClientName: "clientName",
+		UserName:   "userName",
+		UserUID:    "userUID",
+	}
+
+	ctx := api.WithNamespace(api.NewContext(), "default")
+	channel, err := storage.Create(ctx, accessToken)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	var response *oapi.AccessToken
+	select {
+	case r := <-channel:
+		token, ok := r.Object.(*oapi.AccessToken)
+		if !ok {
+			t.Fatalf("Got unexpected type: %#v", r)
+		}
+		response = token
+	case <-time.After(time.Millisecond * 100):
+		t.Fatal("Unexpected timeout from async channel")
+	}
+
+	if response.RefreshToken == "" {
+		t.Fatalf("expected an initial refresh token's one-time plaintext value to be returned")
+	}
+	if refreshTokens.CreatedRefreshToken == nil {
+		t.Fatalf("expected an initial refresh token to be issued to the registry")
+	}
+	expectedRefreshName, _ := SHA256Hasher{}.Hash(response.RefreshToken)
+	if refreshTokens.CreatedRefreshToken.Name != expectedRefreshName {
+		t.Errorf("returned refresh token plaintext does not match the one that was stored")
+	}
+	if refreshTokens.CreatedRefreshToken.AccessTokenName != accessTokens.CreatedAccessToken.Name {
+		t.Errorf("issued refresh token does not reference the newly created access token")
+	}
 }
 
 func TestGetError(t *testing.T) {
@@ -292,7 +349,302 @@ func TestDeleteValid(t *testing.T) {
 		t.Error("Unexpected timeout from async channel")
 	}
 
-	if registry.DeletedAccessTokenName != "foo" {
-		t.Error("Unexpected access token deleted: %s", registry.DeletedAccessTokenName)
+	expectedHash, _ := SHA256Hasher{}.Hash("foo")
+	if registry.DeletedAccessTokenName != expectedHash {
+		t.Errorf("expected registry to receive the hashed token name %q, got %q", expectedHash, registry.DeletedAccessTokenName)
+	}
+}
+
+func TestGetValidExpiryInFuture(t *testing.T) {
+	registry := test.AccessTokenRegistry{
+		AccessToken: &oapi.AccessToken{
+			ObjectMeta: api.ObjectMeta{Name: "accessTokenName"},
+			ExpiresIn:  86400,
+			Expiry:     util.NewTime(time.Now().Add(time.Hour)),
+		},
+	}
+	storage := REST{
+		registry: &registry,
+	}
+	ctx := api.NewContext()
+	obj, err := storage.Get(ctx, "accessTokenName")
+	if err != nil {
+		t.Errorf("got unexpected error: %v", err)
+		return
+	}
+	token := obj.(*oapi.AccessToken)
+	if token.Status != oapi.StatusActive {
+		t.Errorf("expected active status, got %q", token.Status)
+	}
+}
+
+func TestGetExpiryInPast(t *testing.T) {
+	registry := test.AccessTokenRegistry{
+		AccessToken: &oapi.AccessToken{
+			ObjectMeta: api.ObjectMeta{Name: "accessTokenName"},
+			ExpiresIn:  86400,
+			Expiry:     util.NewTime(time.Now().Add(-time.Hour)),
+		},
 	}
-}
\ No newline at end of file
+	storage := REST{
+		registry: &registry,
+	}
+	ctx := api.NewContext()
+	obj, err := storage.Get(ctx, "accessTokenName")
+	if err != nil {
+		t.Errorf("got unexpected error: %v", err)
+		return
+	}
+	token := obj.(*oapi.AccessToken)
+	if token.Status != oapi.StatusExpired {
+		t.Errorf("expected expired status, got %q", token.Status)
+	}
+}
+
+func TestRefreshRotates(t *testing.T) {
+	accessTokens := test.AccessTokenRegistry{}
+	refreshTokens := test.RefreshTokenRegistry{
+		RefreshToken: &oapi.RefreshToken{
+			ObjectMeta:      api.ObjectMeta{Name: "refreshTokenValue"},
+			ClientName:      "clientName",
+			UserName:        "userName",
+			UserUID:         "userUID",
+			AccessTokenName: "oldAccessTokenName",
+		},
+	}
+	storage := REST{
+		registry:      &accessTokens,
+		refreshTokens: &refreshTokens,
+	}
+
+	ctx := api.NewContext()
+	newToken, err := storage.Refresh(ctx, "refreshTokenValue")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if newToken.ClientName != "clientName" || newToken.UserName != "userName" {
+		t.Errorf("new access token did not inherit refresh token identity: %#v", newToken)
+	}
+	if refreshTokens.DeletedRefreshTokenName != "refreshTokenValue" {
+		t.Errorf("expected old refresh token to be deleted, got %q", refreshTokens.DeletedRefreshTokenName)
+	}
+	if refreshTokens.CreatedRefreshToken == nil {
+		t.Fatalf("expected a new refresh token to be issued")
+	}
+	expectedAccessTokenName, _ := SHA256Hasher{}.Hash(newToken.Name)
+	if refreshTokens.CreatedRefreshToken.AccessTokenName != expectedAccessTokenName {
+		t.Errorf("rotated refresh token does not reference the new access token")
+	}
+	if newToken.RefreshToken == "" {
+		t.Fatalf("expected the new refresh token's one-time plaintext value to be returned")
+	}
+	expectedNewRefreshName, _ := SHA256Hasher{}.Hash(newToken.RefreshToken)
+	if refreshTokens.CreatedRefreshToken.Name != expectedNewRefreshName {
+		t.Errorf("returned refresh token plaintext does not match the one that was stored")
+	}
+}
+
+// guardedRefreshTokenRegistry is a tiny in-memory registry, distinct from
+// test.RefreshTokenRegistry, that actually tracks whether its one refresh
+// token has been deleted yet, so a second redemption of the same value can be
+// observed failing rather than succeeding twice.
+type guardedRefreshTokenRegistry struct {
+	token   *oapi.RefreshToken
+	deleted bool
+
+	CreatedRefreshToken *oapi.RefreshToken
+}
+
+func (r *guardedRefreshTokenRegistry) GetRefreshToken(ctx api.Context, name string) (*oapi.RefreshToken, error) {
+	if r.deleted {
+		return nil, errors.New("not found")
+	}
+	return r.token, nil
+}
+
+func (r *guardedRefreshTokenRegistry) DeleteRefreshToken(ctx api.Context, name string) error {
+	if r.deleted {
+		return errors.New("not found")
+	}
+	r.deleted = true
+	return nil
+}
+
+func (r *guardedRefreshTokenRegistry) CreateRefreshToken(ctx api.Context, token *oapi.RefreshToken) error {
+	r.CreatedRefreshToken = token
+	return nil
+}
+
+func TestRefreshSecondRedemptionFails(t *testing.T) {
+	refreshTokens := &guardedRefreshTokenRegistry{
+		token: &oapi.RefreshToken{
+			ObjectMeta: api.ObjectMeta{Name: "refreshTokenValue"},
+			ClientName: "clientName",
+			UserName:   "userName",
+		},
+	}
+	storage := REST{
+		registry:      &test.AccessTokenRegistry{},
+		refreshTokens: refreshTokens,
+	}
+
+	ctx := api.NewContext()
+	if _, err := storage.Refresh(ctx, "refreshTokenValue"); err != nil {
+		t.Fatalf("unexpected error on first redemption: %v", err)
+	}
+	if _, err := storage.Refresh(ctx, "refreshTokenValue"); err == nil {
+		t.Errorf("expected second redemption of the same refresh token to fail")
+	}
+}
+
+func TestGetByPlaintext(t *testing.T) {
+	hashed, _ := SHA256Hasher{}.Hash("mySecretTokenValue")
+	registry := test.AccessTokenRegistry{
+		AccessToken: &oapi.AccessToken{
+			ObjectMeta: api.ObjectMeta{Name: hashed},
+			ClientName: "clientName",
+			UserName:   "userName",
+		},
+	}
+	storage := REST{
+		registry: &registry,
+	}
+
+	ctx := api.NewContext()
+	obj, err := storage.Get(ctx, "mySecretTokenValue")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	token := obj.(*oapi.AccessToken)
+	if token.ClientName != "clientName" || token.UserName != "userName" {
+		t.Errorf("got unexpected token: %#v", token)
+	}
+}
+
+func TestCreateNamespaceMismatch(t *testing.T) {
+	registry := test.AccessTokenRegistry{}
+	storage := REST{
+		registry: &registry,
+	}
+	accessToken := &oapi.AccessToken{
+		ObjectMeta: api.ObjectMeta{Name: "accessTokenName", Namespace: "other"},
+		ClientName: "clientName",
+		UserName:   "userName",
+		UserUID:    "userUID",
+	}
+
+	ctx := api.WithNamespace(api.NewContext(), "default")
+	_, err := storage.Create(ctx, accessToken)
+	if err == nil {
+		t.Errorf("expected namespace mismatch error")
+	}
+}
+
+func TestCreateDefaultNamespaceAssignment(t *testing.T) {
+	registry := test.AccessTokenRegistry{}
+	storage := REST{
+		registry: &registry,
+	}
+	accessToken := &oapi.AccessToken{
+		ObjectMeta: api.ObjectMeta{Name: "accessTokenName"},
+		ClientName: "clientName",
+		UserName:   "userName",
+		UserUID:    "userUID",
+	}
+
+	ctx := api.WithNamespace(api.NewContext(), "default")
+	_, err := storage.Create(ctx, accessToken)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if accessToken.Namespace != "default" {
+		t.Errorf("expected namespace to be defaulted to %q, got %q", "default", accessToken.Namespace)
+	}
+}
+
+func TestGetThreadsNamespace(t *testing.T) {
+	registry := test.AccessTokenRegistry{
+		AccessToken: &oapi.AccessToken{
+			ObjectMeta: api.ObjectMeta{Name: "accessTokenName"},
+		},
+	}
+	storage := REST{
+		registry: &registry,
+	}
+	ctx := api.WithNamespace(api.NewContext(), "alice")
+	if _, err := storage.Get(ctx, "accessTokenName"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if registry.Namespace != "alice" {
+		t.Errorf("expected registry to be called with namespace %q, got %q", "alice", registry.Namespace)
+	}
+}
+
+func TestDeleteCollectionError(t *testing.T) {
+	registry := test.AccessTokenRegistry{
+		Err: errors.New("Sample Error"),
+	}
+	storage := REST{
+		registry: &registry,
+	}
+
+	ctx := api.NewContext()
+	channel, err := storage.DeleteCollection(ctx, labels.Everything(), labels.Everything())
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	select {
+	case r := <-channel:
+		switch r := r.Object.(type) {
+		case *api.Status:
+			if r.Message == registry.Err.Error() {
+				// expected case
+			} else {
+				t.Errorf("Got back unexpected error: %#v", r)
+			}
+		default:
+			t.Errorf("Got back non-status result: %v", r)
+		}
+	case <-time.After(time.Millisecond * 100):
+		t.Error("Unexpected timeout from async channel")
+	}
+}
+
+func TestDeleteCollectionLogsOutEverywhere(t *testing.T) {
+	registry := test.AccessTokenRegistry{
+		AccessTokens: &oapi.AccessTokenList{
+			Items: []oapi.AccessToken{
+				{ObjectMeta: api.ObjectMeta{Name: "tokenOne"}, UserName: "alice"},
+				{ObjectMeta: api.ObjectMeta{Name: "tokenTwo"}, UserName: "alice"},
+			},
+		},
+	}
+	storage := REST{
+		registry: &registry,
+	}
+
+	ctx := api.NewContext()
+	channel, err := storage.DeleteCollection(ctx, labels.Everything(), labels.Everything())
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	select {
+	case r := <-channel:
+		switch r := r.Object.(type) {
+		case *oapi.AccessTokenList:
+			if len(r.Items) != 2 {
+				t.Errorf("expected 2 deleted tokens, got %#v", r)
+			}
+		default:
+			t.Errorf("Got back unexpected result: %v", r)
+		}
+	case <-time.After(time.Millisecond * 100):
+		t.Error("Unexpected timeout from async channel")
+	}
+
+	if len(registry.DeletedAccessTokenNames) != 2 {
+		t.Errorf("expected both of alice's tokens to be revoked, got %v", registry.DeletedAccessTokenNames)
+	}
+}
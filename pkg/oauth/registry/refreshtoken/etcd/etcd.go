@@ -0,0 +1,42 @@
+package etcd
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+
+	oapi "github.com/openshift/origin/pkg/oauth/api"
+)
+
+const etcdPrefix = "/registry/refreshtokens"
+
+// Etcd implements the refreshtoken.Registry interface backed by etcd.
+type Etcd struct {
+	tools.EtcdHelper
+}
+
+// New returns a new etcd-backed RefreshToken registry.
+func New(helper tools.EtcdHelper) *Etcd {
+	return &Etcd{EtcdHelper: helper}
+}
+
+func makeRefreshTokenKey(name string) string {
+	return fmt.Sprintf("%s/%s", etcdPrefix, name)
+}
+
+func (r *Etcd) GetRefreshToken(ctx api.Context, name string) (*oapi.RefreshToken, error) {
+	token := &oapi.RefreshToken{}
+	if err := r.ExtractObj(makeRefreshTokenKey(name), token, false); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+func (r *Etcd) CreateRefreshToken(ctx api.Context, token *oapi.RefreshToken) error {
+	return r.CreateObj(makeRefreshTokenKey(token.Name), token, 0)
+}
+
+func (r *Etcd) DeleteRefreshToken(ctx api.Context, name string) error {
+	return r.Delete(makeRefreshTokenKey(name), false)
+}
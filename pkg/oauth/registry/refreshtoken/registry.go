@@ -0,0 +1,18 @@
+package refreshtoken
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+
+	oapi "github.com/openshift/origin/pkg/oauth/api"
+)
+
+// Registry is an interface implemented by things that know how to store RefreshToken objects.
+type Registry interface {
+	// GetRefreshToken retrieves a specific refresh token.
+	GetRefreshToken(ctx api.Context, name string) (*oapi.RefreshToken, error)
+	// CreateRefreshToken creates a new refresh token.
+	CreateRefreshToken(ctx api.Context, token *oapi.RefreshToken) error
+	// DeleteRefreshToken deletes a refresh token. It is called once a refresh
+	// token has been redeemed so it cannot be replayed.
+	DeleteRefreshToken(ctx api.Context, name string) error
+}
@@ -0,0 +1,62 @@
+package test
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+
+	oapi "github.com/openshift/origin/pkg/oauth/api"
+)
+
+// AccessTokenRegistry is a test double implementing accesstoken.Registry.
+type AccessTokenRegistry struct {
+	Err          error
+	AccessToken  *oapi.AccessToken
+	AccessTokens *oapi.AccessTokenList
+
+	// Namespace records the namespace of the context passed to the most recent call.
+	Namespace string
+
+	DeletedAccessTokenName   string
+	DeletedAccessTokenReason string
+	DeletedAccessTokenNames  []string
+
+	CreatedAccessToken *oapi.AccessToken
+}
+
+func (r *AccessTokenRegistry) ListAccessTokens(ctx api.Context, label, field labels.Selector) (*oapi.AccessTokenList, error) {
+	r.Namespace = api.NamespaceValue(ctx)
+	return r.AccessTokens, r.Err
+}
+
+func (r *AccessTokenRegistry) GetAccessToken(ctx api.Context, name string) (*oapi.AccessToken, error) {
+	r.Namespace = api.NamespaceValue(ctx)
+	return r.AccessToken, r.Err
+}
+
+func (r *AccessTokenRegistry) CreateAccessToken(ctx api.Context, token *oapi.AccessToken) error {
+	r.Namespace = api.NamespaceValue(ctx)
+	r.CreatedAccessToken = token
+	return r.Err
+}
+
+func (r *AccessTokenRegistry) DeleteAccessToken(ctx api.Context, name, reason string) error {
+	r.Namespace = api.NamespaceValue(ctx)
+	r.DeletedAccessTokenName = name
+	r.DeletedAccessTokenReason = reason
+	return r.Err
+}
+
+func (r *AccessTokenRegistry) DeleteAccessTokens(ctx api.Context, label, field labels.Selector, reason string) (*oapi.AccessTokenList, error) {
+	r.Namespace = api.NamespaceValue(ctx)
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	deleted := &oapi.AccessTokenList{}
+	if r.AccessTokens != nil {
+		deleted.Items = r.AccessTokens.Items
+	}
+	for _, token := range deleted.Items {
+		r.DeletedAccessTokenNames = append(r.DeletedAccessTokenNames, token.Name)
+	}
+	return deleted, nil
+}
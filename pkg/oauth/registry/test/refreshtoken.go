@@ -0,0 +1,30 @@
+package test
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+
+	oapi "github.com/openshift/origin/pkg/oauth/api"
+)
+
+// RefreshTokenRegistry is a test double implementing refreshtoken.Registry.
+type RefreshTokenRegistry struct {
+	Err          error
+	RefreshToken *oapi.RefreshToken
+
+	CreatedRefreshToken     *oapi.RefreshToken
+	DeletedRefreshTokenName string
+}
+
+func (r *RefreshTokenRegistry) GetRefreshToken(ctx api.Context, name string) (*oapi.RefreshToken, error) {
+	return r.RefreshToken, r.Err
+}
+
+func (r *RefreshTokenRegistry) CreateRefreshToken(ctx api.Context, token *oapi.RefreshToken) error {
+	r.CreatedRefreshToken = token
+	return r.Err
+}
+
+func (r *RefreshTokenRegistry) DeleteRefreshToken(ctx api.Context, name string) error {
+	r.DeletedRefreshTokenName = name
+	return r.Err
+}